@@ -0,0 +1,106 @@
+package goose
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestClickHouseCreateVersionTableSQL(t *testing.T) {
+	ch := ClickHouseDialect{}
+	stmt := ch.CreateVersionTableSQL("goose_db_version")
+	for _, want := range []string{"CREATE TABLE goose_db_version", "version_id Int64", "is_applied UInt8", "tstamp DateTime DEFAULT now()", "ENGINE = ReplacingMergeTree() ORDER BY version_id"} {
+		if !strings.Contains(stmt, want) {
+			t.Errorf("CreateVersionTableSQL() = %q, missing %q", stmt, want)
+		}
+	}
+}
+
+func TestClickHouseCreateVersionTableSQLOnCluster(t *testing.T) {
+	ch := ClickHouseDialect{OnCluster: "my_cluster"}
+	stmt := ch.CreateVersionTableSQL("goose_db_version")
+	if !strings.Contains(stmt, "ON CLUSTER my_cluster") {
+		t.Errorf("CreateVersionTableSQL() = %q, missing ON CLUSTER clause", stmt)
+	}
+}
+
+func TestClickHouseCreateTableSQLNullableColumn(t *testing.T) {
+	ch := ClickHouseDialect{}
+	stmt, err := ch.CreateTableSQL("events", []Column{
+		{Name: "payload", Type: ColumnText, Nullable: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateTableSQL returned unexpected error: %v", err)
+	}
+	if !strings.Contains(stmt, "payload Nullable(String)") {
+		t.Errorf("CreateTableSQL() = %q, want Nullable(String) column", stmt)
+	}
+	if !strings.Contains(stmt, "ENGINE = MergeTree() ORDER BY tuple();") {
+		t.Errorf("CreateTableSQL() = %q, missing MergeTree engine clause", stmt)
+	}
+}
+
+func TestMSSQLCreateVersionTableSQL(t *testing.T) {
+	ms := MSSQLDialect{}
+	stmt := ms.CreateVersionTableSQL("goose_db_version")
+	for _, want := range []string{"id INT IDENTITY(1,1) NOT NULL", "is_applied BIT NOT NULL", "tstamp DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME()"} {
+		if !strings.Contains(stmt, want) {
+			t.Errorf("CreateVersionTableSQL() = %q, missing %q", stmt, want)
+		}
+	}
+}
+
+func TestMSSQLInsertVersionSQLUsesNamedPlaceholders(t *testing.T) {
+	ms := MSSQLDialect{}
+	want := "INSERT INTO goose_db_version (version_id, is_applied) VALUES (@p1, @p2);"
+	if got := ms.InsertVersionSQL("goose_db_version"); got != want {
+		t.Errorf("InsertVersionSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestMSSQLAlterTableChangeColumnRejectsDefault(t *testing.T) {
+	ms := MSSQLDialect{}
+	_, err := ms.AlterTableSQL("posts", []TableChange{
+		{Kind: ChangeColumn, Name: "body", Column: Column{Type: ColumnText, Default: "''"}},
+	})
+	if err == nil {
+		t.Fatal("AlterTableSQL() with a default on ChangeColumn = nil error, want error")
+	}
+}
+
+func TestClickHouseDbVersionQueryUsesFinal(t *testing.T) {
+	// ReplacingMergeTree only dedupes rows for a version_id on
+	// background merges, so List() must query with FINAL to see the
+	// latest row right after goose's usual insert+delete cycle.
+	name, fd := registerFakeLockDriver()
+	db, err := sql.Open(name, "fake")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := ClickHouseDialect{}.DbVersionQuery(db, "goose_db_version")
+	if err != nil {
+		t.Fatalf("DbVersionQuery: %v", err)
+	}
+	rows.Close()
+
+	queries := fd.connIDsFor("FROM goose_db_version FINAL")
+	if len(queries) != 1 {
+		t.Fatalf("DbVersionQuery did not run a FROM ... FINAL query")
+	}
+}
+
+func TestMSSQLAlterTableChangeColumnNullability(t *testing.T) {
+	ms := MSSQLDialect{}
+	stmt, err := ms.AlterTableSQL("posts", []TableChange{
+		{Kind: ChangeColumn, Name: "body", Column: Column{Type: ColumnText, Nullable: true}},
+	})
+	if err != nil {
+		t.Fatalf("AlterTableSQL returned unexpected error: %v", err)
+	}
+	want := "ALTER TABLE posts ALTER COLUMN body NVARCHAR(MAX) NULL;"
+	if stmt != want {
+		t.Errorf("AlterTableSQL() = %q, want %q", stmt, want)
+	}
+}
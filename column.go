@@ -0,0 +1,44 @@
+package goose
+
+// ColumnType enumerates the portable column types goose knows how to
+// render per-dialect. Dialects map each type to their own native type
+// name (and size/precision conventions) in CreateTableSQL/AlterTableSQL.
+type ColumnType int
+
+const (
+	ColumnInteger ColumnType = iota
+	ColumnText
+	ColumnDatetime
+	ColumnBoolean
+)
+
+// Column describes a single column in a portable, dialect-agnostic way.
+// It is rendered to DDL by a SQLDialect's CreateTableSQL/AlterTableSQL.
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Size     int // optional, e.g. VARCHAR size; 0 means dialect default
+	Nullable bool
+	Default  string // raw default literal/expression, empty if none
+
+	// DefaultCurrentTimestamp renders the dialect's "now" default
+	// (e.g. now(), CURRENT_TIMESTAMP, datetime('now')) instead of Default.
+	DefaultCurrentTimestamp bool
+}
+
+// ChangeKind identifies the kind of alteration a TableChange represents.
+type ChangeKind int
+
+const (
+	AddColumn ChangeKind = iota
+	ChangeColumn
+	DropColumn
+)
+
+// TableChange describes a single ALTER TABLE operation. Column is only
+// required for AddColumn and ChangeColumn; DropColumn only needs Name.
+type TableChange struct {
+	Kind   ChangeKind
+	Name   string
+	Column Column
+}
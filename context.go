@@ -0,0 +1,49 @@
+package goose
+
+import "database/sql"
+
+// MigrationContext is passed to Go migrations' up/down funcs. Its
+// schema helpers render the active SQLDialect's DDL (see
+// GetDialect/SetDialect/SetCustomDialect) and execute it against DB, so
+// a single Go migration can target Postgres/MySQL/SQLite/Redshift/TiDB/
+// ClickHouse/MSSQL without hand-rolling dialect-specific SQL strings.
+type MigrationContext struct {
+	DB *sql.DB
+}
+
+// CreateTable creates table with the given columns, rendered through
+// the active dialect.
+func (c *MigrationContext) CreateTable(table string, cols ...Column) error {
+	stmt, err := GetDialect().CreateTableSQL(table, cols)
+	if err != nil {
+		return err
+	}
+	_, err = c.DB.Exec(stmt)
+	return err
+}
+
+// AlterTable applies changes to table, rendered through the active
+// dialect.
+func (c *MigrationContext) AlterTable(table string, changes ...TableChange) error {
+	stmt, err := GetDialect().AlterTableSQL(table, changes)
+	if err != nil {
+		return err
+	}
+	_, err = c.DB.Exec(stmt)
+	return err
+}
+
+// AddColumn adds col to table.
+func (c *MigrationContext) AddColumn(table string, col Column) error {
+	return c.AlterTable(table, TableChange{Kind: AddColumn, Column: col})
+}
+
+// ChangeColumn alters the named column in table to match col.
+func (c *MigrationContext) ChangeColumn(table, name string, col Column) error {
+	return c.AlterTable(table, TableChange{Kind: ChangeColumn, Name: name, Column: col})
+}
+
+// DropColumn drops the named column from table.
+func (c *MigrationContext) DropColumn(table, name string) error {
+	return c.AlterTable(table, TableChange{Kind: DropColumn, Name: name})
+}
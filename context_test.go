@@ -0,0 +1,141 @@
+package goose
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func newFakeContextDB(t *testing.T) (*sql.DB, *fakeLockDriver) {
+	t.Helper()
+	name, fd := registerFakeLockDriver()
+	db, err := sql.Open(name, "fake")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fd
+}
+
+// withDialect temporarily installs d as the active dialect for the
+// duration of the test, restoring whatever was active before.
+func withDialect(t *testing.T, d SQLDialect) {
+	t.Helper()
+	prev := GetDialect()
+	SetCustomDialect(d)
+	t.Cleanup(func() { SetCustomDialect(prev) })
+}
+
+func TestMigrationContextCreateTableExecutesRenderedSQL(t *testing.T) {
+	withDialect(t, PostgresDialect{})
+	db, fd := newFakeContextDB(t)
+
+	ctx := &MigrationContext{DB: db}
+	if err := ctx.CreateTable("posts", Column{Name: "title", Type: ColumnText}); err != nil {
+		t.Fatalf("CreateTable: %v", err)
+	}
+
+	if ids := fd.connIDsFor("CREATE TABLE posts"); len(ids) != 1 {
+		t.Fatalf("got %d CREATE TABLE posts calls, want 1", len(ids))
+	}
+}
+
+func TestMigrationContextCreateTablePropagatesDialectError(t *testing.T) {
+	withDialect(t, PostgresDialect{})
+	db, fd := newFakeContextDB(t)
+
+	ctx := &MigrationContext{DB: db}
+	// An empty column name is rejected by buildColumnDef before any SQL
+	// is rendered, let alone executed.
+	err := ctx.CreateTable("posts", Column{Type: ColumnText})
+	if err == nil {
+		t.Fatal("CreateTable() with an invalid column = nil error, want error")
+	}
+	if ids := fd.connIDsFor("CREATE TABLE"); len(ids) != 0 {
+		t.Fatalf("CreateTable() executed %d statements despite a rendering error, want 0", len(ids))
+	}
+}
+
+func TestMigrationContextAlterTableExecutesRenderedSQL(t *testing.T) {
+	withDialect(t, PostgresDialect{})
+	db, fd := newFakeContextDB(t)
+
+	ctx := &MigrationContext{DB: db}
+	if err := ctx.AlterTable("posts", TableChange{Kind: AddColumn, Column: Column{Name: "views", Type: ColumnInteger}}); err != nil {
+		t.Fatalf("AlterTable: %v", err)
+	}
+
+	if ids := fd.connIDsFor("ALTER TABLE posts"); len(ids) != 1 {
+		t.Fatalf("got %d ALTER TABLE posts calls, want 1", len(ids))
+	}
+}
+
+func TestMigrationContextAlterTablePropagatesDialectError(t *testing.T) {
+	withDialect(t, PostgresDialect{})
+	db, _ := newFakeContextDB(t)
+
+	ctx := &MigrationContext{DB: db}
+	if err := ctx.AlterTable("posts"); err == nil {
+		t.Fatal("AlterTable() with no changes = nil error, want error")
+	}
+}
+
+func TestMigrationContextAddColumn(t *testing.T) {
+	withDialect(t, PostgresDialect{})
+	db, fd := newFakeContextDB(t)
+
+	ctx := &MigrationContext{DB: db}
+	if err := ctx.AddColumn("posts", Column{Name: "views", Type: ColumnInteger}); err != nil {
+		t.Fatalf("AddColumn: %v", err)
+	}
+	if ids := fd.connIDsFor("ADD COLUMN views INTEGER"); len(ids) != 1 {
+		t.Fatalf("AddColumn did not execute the expected ADD COLUMN clause")
+	}
+}
+
+func TestMigrationContextChangeColumn(t *testing.T) {
+	withDialect(t, MySQLDialect{})
+	db, fd := newFakeContextDB(t)
+
+	ctx := &MigrationContext{DB: db}
+	if err := ctx.ChangeColumn("posts", "body", Column{Type: ColumnText}); err != nil {
+		t.Fatalf("ChangeColumn: %v", err)
+	}
+	if ids := fd.connIDsFor("MODIFY COLUMN body TEXT"); len(ids) != 1 {
+		t.Fatalf("ChangeColumn did not execute the expected MODIFY COLUMN clause")
+	}
+}
+
+func TestMigrationContextDropColumn(t *testing.T) {
+	withDialect(t, PostgresDialect{})
+	db, fd := newFakeContextDB(t)
+
+	ctx := &MigrationContext{DB: db}
+	if err := ctx.DropColumn("posts", "body"); err != nil {
+		t.Fatalf("DropColumn: %v", err)
+	}
+
+	found := false
+	for _, q := range queriesFor(fd, "posts") {
+		if strings.Contains(q, "DROP COLUMN body") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("DropColumn did not execute the expected DROP COLUMN clause")
+	}
+}
+
+// queriesFor returns every recorded query containing substr, for
+// assertions that want to inspect the text rather than just count calls.
+func queriesFor(d *fakeLockDriver, substr string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var qs []string
+	for _, c := range d.calls {
+		if strings.Contains(c.query, substr) {
+			qs = append(qs, c.query)
+		}
+	}
+	return qs
+}
@@ -3,14 +3,43 @@ package goose
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 )
 
 // SQLDialect abstracts the details of specific SQL dialects
-// for goose's few SQL specific statements
+// for goose's few SQL specific statements. Version tracking itself is
+// delegated to a VersionStore (see versionstore.go); these methods
+// exist to let the default SQLVersionStore render the right SQL for a
+// given dialect against an arbitrary, caller-chosen table name.
 type SQLDialect interface {
-	CreateVersionTableSQL() string // sql string to create the goose_db_version table
-	InsertVersionSQL() string      // sql string to insert the initial version table row
-	DbVersionQuery(db *sql.DB) (*sql.Rows, error)
+	CreateVersionTableSQL(tableName string) string // sql string to create the version table
+	InsertVersionSQL(tableName string) string       // sql string to insert a version row
+	DeleteVersionSQL(tableName string) string       // sql string to delete a version row
+	DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error)
+
+	// CreateTableSQL renders a CREATE TABLE statement for the given
+	// columns in this dialect's native syntax.
+	CreateTableSQL(table string, cols []Column) (string, error)
+	// AlterTableSQL renders an ALTER TABLE statement applying changes
+	// in this dialect's native syntax.
+	AlterTableSQL(table string, changes []TableChange) (string, error)
+
+	// SupportsTransactionalDDL reports whether this dialect can run
+	// schema-changing statements inside a transaction that rolls back
+	// cleanly on failure.
+	SupportsTransactionalDDL() bool
+	// AcquireLock takes a migration lock so that multiple goose
+	// instances rolling out concurrently don't race on the version
+	// table or double-apply DDL. Some dialects' locks are tied to the
+	// connection that took them out (e.g. Postgres/MySQL/TiDB's session
+	// locks, SQLite's BEGIN EXCLUSIVE, MSSQL's sp_getapplock); for
+	// those, conn is the *sql.Conn the lock was acquired on and every
+	// subsequent statement that depends on the lock (including any DDL
+	// sqlite3 runs while holding it) must run on conn, not db. conn is
+	// nil for dialects whose lock isn't connection-scoped (including the
+	// no-op fallbacks). unlock releases the lock, and closes conn if one
+	// was returned; callers must call it (typically via defer) once done.
+	AcquireLock(db *sql.DB) (conn *sql.Conn, unlock func() error, err error)
 }
 
 var dialect SQLDialect = &PostgresDialect{}
@@ -38,6 +67,10 @@ func SetDialect(d string) error {
 		dialect = &RedshiftDialect{}
 	case "tidb":
 		dialect = &TiDBDialect{}
+	case "clickhouse":
+		dialect = &ClickHouseDialect{}
+	case "mssql":
+		dialect = &MSSQLDialect{}
 	default:
 		return fmt.Errorf("%q: unknown dialect", d)
 	}
@@ -52,22 +85,26 @@ func SetDialect(d string) error {
 // PostgresDialect struct.
 type PostgresDialect struct{}
 
-func (pg PostgresDialect) CreateVersionTableSQL() string {
-	return `CREATE TABLE goose_db_version (
+func (pg PostgresDialect) CreateVersionTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE %s (
             	id serial NOT NULL,
                 version_id bigint NOT NULL,
                 is_applied boolean NOT NULL,
                 tstamp timestamp NULL default now(),
                 PRIMARY KEY(id)
-            );`
+            );`, tableName)
 }
 
-func (pg PostgresDialect) InsertVersionSQL() string {
-	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, $2);"
+func (pg PostgresDialect) InsertVersionSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES ($1, $2);", tableName)
 }
 
-func (pg PostgresDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
-	rows, err := db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC")
+func (pg PostgresDialect) DeleteVersionSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE version_id = $1;", tableName)
+}
+
+func (pg PostgresDialect) DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id, is_applied from %s ORDER BY id DESC", tableName))
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +112,75 @@ func (pg PostgresDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 	return rows, err
 }
 
+func (pg PostgresDialect) columnType(c Column) string {
+	switch c.Type {
+	case ColumnInteger:
+		return integerTypeBySize(c.Size, "SMALLINT", "INTEGER", "BIGINT")
+	case ColumnText:
+		return textTypeBySize(c.Size, "VARCHAR", "TEXT")
+	case ColumnDatetime:
+		return "TIMESTAMP"
+	case ColumnBoolean:
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+func (pg PostgresDialect) CreateTableSQL(table string, cols []Column) (string, error) {
+	defs := make([]string, 0, len(cols)+1)
+	defs = append(defs, "id serial NOT NULL")
+	for _, c := range cols {
+		def, err := buildColumnDef("postgres", pg.columnType, c)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+	}
+	defs = append(defs, "PRIMARY KEY(id)")
+
+	return fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", table, strings.Join(defs, ",\n    ")), nil
+}
+
+func (pg PostgresDialect) AlterTableSQL(table string, changes []TableChange) (string, error) {
+	if len(changes) == 0 {
+		return "", fmt.Errorf("goose: no changes given for table %q", table)
+	}
+
+	clauses := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		switch ch.Kind {
+		case AddColumn:
+			def, err := buildColumnDef("postgres", pg.columnType, ch.Column)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "ADD COLUMN "+def)
+		case ChangeColumn:
+			clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s TYPE %s", ch.Name, pg.columnType(ch.Column)))
+			if ch.Column.Nullable {
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", ch.Name))
+			} else {
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", ch.Name))
+			}
+			switch {
+			case ch.Column.DefaultCurrentTimestamp:
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT now()", ch.Name))
+			case ch.Column.Default != "":
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", ch.Name, ch.Column.Default))
+			default:
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", ch.Name))
+			}
+		case DropColumn:
+			clauses = append(clauses, "DROP COLUMN "+ch.Name)
+		default:
+			return "", fmt.Errorf("goose: unknown table change kind for table %q", table)
+		}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s %s;", table, strings.Join(clauses, ", ")), nil
+}
+
 ////////////////////////////
 // MySQL
 ////////////////////////////
@@ -82,22 +188,26 @@ func (pg PostgresDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 // MySQLDialect struct.
 type MySQLDialect struct{}
 
-func (m MySQLDialect) CreateVersionTableSQL() string {
-	return `CREATE TABLE goose_db_version (
+func (m MySQLDialect) CreateVersionTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE %s (
                 id serial NOT NULL,
                 version_id bigint NOT NULL,
                 is_applied boolean NOT NULL,
                 tstamp timestamp NULL default now(),
                 PRIMARY KEY(id)
-            );`
+            );`, tableName)
+}
+
+func (m MySQLDialect) InsertVersionSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES (?, ?);", tableName)
 }
 
-func (m MySQLDialect) InsertVersionSQL() string {
-	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?);"
+func (m MySQLDialect) DeleteVersionSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE version_id = ?;", tableName)
 }
 
-func (m MySQLDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
-	rows, err := db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC")
+func (m MySQLDialect) DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id, is_applied from %s ORDER BY id DESC", tableName))
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +215,68 @@ func (m MySQLDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 	return rows, err
 }
 
+func (m MySQLDialect) columnType(c Column) string {
+	switch c.Type {
+	case ColumnInteger:
+		return integerTypeBySize(c.Size, "TINYINT", "INT", "BIGINT")
+	case ColumnText:
+		return textTypeBySize(c.Size, "VARCHAR", "TEXT")
+	case ColumnDatetime:
+		return "DATETIME"
+	case ColumnBoolean:
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+func (m MySQLDialect) CreateTableSQL(table string, cols []Column) (string, error) {
+	defs := make([]string, 0, len(cols)+1)
+	defs = append(defs, "id serial NOT NULL")
+	for _, c := range cols {
+		def, err := buildColumnDef("mysql", m.columnType, c)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+	}
+	defs = append(defs, "PRIMARY KEY(id)")
+
+	return fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", table, strings.Join(defs, ",\n    ")), nil
+}
+
+func (m MySQLDialect) AlterTableSQL(table string, changes []TableChange) (string, error) {
+	if len(changes) == 0 {
+		return "", fmt.Errorf("goose: no changes given for table %q", table)
+	}
+
+	clauses := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		switch ch.Kind {
+		case AddColumn:
+			def, err := buildColumnDef("mysql", m.columnType, ch.Column)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "ADD COLUMN "+def)
+		case ChangeColumn:
+			col := ch.Column
+			col.Name = ch.Name
+			def, err := buildColumnDef("mysql", m.columnType, col)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "MODIFY COLUMN "+def)
+		case DropColumn:
+			clauses = append(clauses, "DROP COLUMN "+ch.Name)
+		default:
+			return "", fmt.Errorf("goose: unknown table change kind for table %q", table)
+		}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s %s;", table, strings.Join(clauses, ", ")), nil
+}
+
 ////////////////////////////
 // sqlite3
 ////////////////////////////
@@ -112,21 +284,25 @@ func (m MySQLDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 // Sqlite3Dialect struct.
 type Sqlite3Dialect struct{}
 
-func (m Sqlite3Dialect) CreateVersionTableSQL() string {
-	return `CREATE TABLE goose_db_version (
+func (m Sqlite3Dialect) CreateVersionTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE %s (
                 id INTEGER PRIMARY KEY AUTOINCREMENT,
                 version_id INTEGER NOT NULL,
                 is_applied INTEGER NOT NULL,
                 tstamp TIMESTAMP DEFAULT (datetime('now'))
-            );`
+            );`, tableName)
 }
 
-func (m Sqlite3Dialect) InsertVersionSQL() string {
-	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?);"
+func (m Sqlite3Dialect) InsertVersionSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES (?, ?);", tableName)
 }
 
-func (m Sqlite3Dialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
-	rows, err := db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC")
+func (m Sqlite3Dialect) DeleteVersionSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE version_id = ?;", tableName)
+}
+
+func (m Sqlite3Dialect) DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id, is_applied from %s ORDER BY id DESC", tableName))
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +310,60 @@ func (m Sqlite3Dialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 	return rows, err
 }
 
+func (m Sqlite3Dialect) columnType(c Column) string {
+	switch c.Type {
+	case ColumnInteger:
+		// SQLite uses type affinity rather than fixed-width integer
+		// types, so every integer column affinities to INTEGER
+		// regardless of the requested size.
+		return "INTEGER"
+	case ColumnText:
+		return "TEXT"
+	case ColumnDatetime:
+		return "TIMESTAMP"
+	case ColumnBoolean:
+		return "INTEGER"
+	default:
+		return "TEXT"
+	}
+}
+
+func (m Sqlite3Dialect) CreateTableSQL(table string, cols []Column) (string, error) {
+	defs := make([]string, 0, len(cols)+1)
+	defs = append(defs, "id INTEGER PRIMARY KEY AUTOINCREMENT")
+	for _, c := range cols {
+		def, err := buildColumnDef("sqlite3", m.columnType, c)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", table, strings.Join(defs, ",\n    ")), nil
+}
+
+func (m Sqlite3Dialect) AlterTableSQL(table string, changes []TableChange) (string, error) {
+	// SQLite only allows a single action per ALTER TABLE statement.
+	if len(changes) != 1 {
+		return "", fmt.Errorf("goose: sqlite3 requires exactly one change per ALTER TABLE statement, got %d for table %q", len(changes), table)
+	}
+
+	switch ch := changes[0]; ch.Kind {
+	case AddColumn:
+		def, err := buildColumnDef("sqlite3", m.columnType, ch.Column)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, def), nil
+	case DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, ch.Name), nil
+	case ChangeColumn:
+		return "", fmt.Errorf("goose: sqlite3 does not support altering a column's type or nullability, recreate %q instead", table)
+	default:
+		return "", fmt.Errorf("goose: unknown table change kind for table %q", table)
+	}
+}
+
 ////////////////////////////
 // Redshift
 ////////////////////////////
@@ -141,22 +371,26 @@ func (m Sqlite3Dialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 // RedshiftDialect struct.
 type RedshiftDialect struct{}
 
-func (rs RedshiftDialect) CreateVersionTableSQL() string {
-	return `CREATE TABLE goose_db_version (
+func (rs RedshiftDialect) CreateVersionTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE %s (
             	id integer NOT NULL identity(1, 1),
                 version_id bigint NOT NULL,
                 is_applied boolean NOT NULL,
                 tstamp timestamp NULL default sysdate,
                 PRIMARY KEY(id)
-            );`
+            );`, tableName)
+}
+
+func (rs RedshiftDialect) InsertVersionSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES ($1, $2);", tableName)
 }
 
-func (rs RedshiftDialect) InsertVersionSQL() string {
-	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, $2);"
+func (rs RedshiftDialect) DeleteVersionSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE version_id = $1;", tableName)
 }
 
-func (rs RedshiftDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
-	rows, err := db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC")
+func (rs RedshiftDialect) DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id, is_applied from %s ORDER BY id DESC", tableName))
 	if err != nil {
 		return nil, err
 	}
@@ -164,6 +398,58 @@ func (rs RedshiftDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 	return rows, err
 }
 
+func (rs RedshiftDialect) columnType(c Column) string {
+	switch c.Type {
+	case ColumnInteger:
+		return integerTypeBySize(c.Size, "SMALLINT", "INTEGER", "BIGINT")
+	case ColumnText:
+		return textTypeBySize(c.Size, "VARCHAR", "VARCHAR(256)")
+	case ColumnDatetime:
+		return "TIMESTAMP"
+	case ColumnBoolean:
+		return "BOOLEAN"
+	default:
+		return "VARCHAR(256)"
+	}
+}
+
+func (rs RedshiftDialect) CreateTableSQL(table string, cols []Column) (string, error) {
+	defs := make([]string, 0, len(cols)+1)
+	defs = append(defs, "id integer NOT NULL identity(1, 1)")
+	for _, c := range cols {
+		def, err := buildColumnDef("redshift", rs.columnType, c)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+	}
+	defs = append(defs, "PRIMARY KEY(id)")
+
+	return fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", table, strings.Join(defs, ",\n    ")), nil
+}
+
+func (rs RedshiftDialect) AlterTableSQL(table string, changes []TableChange) (string, error) {
+	// Redshift only allows a single ADD/DROP COLUMN action per statement.
+	if len(changes) != 1 {
+		return "", fmt.Errorf("goose: redshift requires exactly one change per ALTER TABLE statement, got %d for table %q", len(changes), table)
+	}
+
+	switch ch := changes[0]; ch.Kind {
+	case AddColumn:
+		def, err := buildColumnDef("redshift", rs.columnType, ch.Column)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, def), nil
+	case DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, ch.Name), nil
+	case ChangeColumn:
+		return "", fmt.Errorf("goose: redshift does not support altering a column's type, add a new column and backfill %q instead", table)
+	default:
+		return "", fmt.Errorf("goose: unknown table change kind for table %q", table)
+	}
+}
+
 ////////////////////////////
 // TiDB
 ////////////////////////////
@@ -171,25 +457,327 @@ func (rs RedshiftDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
 // TiDBDialect struct.
 type TiDBDialect struct{}
 
-func (m TiDBDialect) CreateVersionTableSQL() string {
-	return `CREATE TABLE goose_db_version (
+func (m TiDBDialect) CreateVersionTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE %s (
                 id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE,
                 version_id bigint NOT NULL,
                 is_applied boolean NOT NULL,
                 tstamp timestamp NULL default now(),
                 PRIMARY KEY(id)
-            );`
+            );`, tableName)
 }
 
-func (m TiDBDialect) InsertVersionSQL() string {
-	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?);"
+func (m TiDBDialect) InsertVersionSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES (?, ?);", tableName)
 }
 
-func (m TiDBDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
-	rows, err := db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC")
+func (m TiDBDialect) DeleteVersionSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE version_id = ?;", tableName)
+}
+
+func (m TiDBDialect) DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id, is_applied from %s ORDER BY id DESC", tableName))
 	if err != nil {
 		return nil, err
 	}
 
 	return rows, err
 }
+
+func (m TiDBDialect) columnType(c Column) string {
+	switch c.Type {
+	case ColumnInteger:
+		return integerTypeBySize(c.Size, "TINYINT", "INT", "BIGINT")
+	case ColumnText:
+		return textTypeBySize(c.Size, "VARCHAR", "TEXT")
+	case ColumnDatetime:
+		return "DATETIME"
+	case ColumnBoolean:
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+func (m TiDBDialect) CreateTableSQL(table string, cols []Column) (string, error) {
+	defs := make([]string, 0, len(cols)+1)
+	defs = append(defs, "id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE")
+	for _, c := range cols {
+		def, err := buildColumnDef("tidb", m.columnType, c)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+	}
+	defs = append(defs, "PRIMARY KEY(id)")
+
+	return fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", table, strings.Join(defs, ",\n    ")), nil
+}
+
+func (m TiDBDialect) AlterTableSQL(table string, changes []TableChange) (string, error) {
+	if len(changes) == 0 {
+		return "", fmt.Errorf("goose: no changes given for table %q", table)
+	}
+
+	clauses := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		switch ch.Kind {
+		case AddColumn:
+			def, err := buildColumnDef("tidb", m.columnType, ch.Column)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "ADD COLUMN "+def)
+		case ChangeColumn:
+			col := ch.Column
+			col.Name = ch.Name
+			def, err := buildColumnDef("tidb", m.columnType, col)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "MODIFY COLUMN "+def)
+		case DropColumn:
+			clauses = append(clauses, "DROP COLUMN "+ch.Name)
+		default:
+			return "", fmt.Errorf("goose: unknown table change kind for table %q", table)
+		}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s %s;", table, strings.Join(clauses, ", ")), nil
+}
+
+////////////////////////////
+// ClickHouse
+////////////////////////////
+
+// ClickHouseDialect struct. OnCluster, if set, is rendered as an
+// ON CLUSTER clause on every DDL statement so changes replicate across
+// a ClickHouse cluster instead of applying to a single node.
+type ClickHouseDialect struct {
+	OnCluster string
+}
+
+func (ch ClickHouseDialect) onClusterClause() string {
+	if ch.OnCluster == "" {
+		return ""
+	}
+	return fmt.Sprintf(" ON CLUSTER %s", ch.OnCluster)
+}
+
+func (ch ClickHouseDialect) CreateVersionTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE %s%s (
+                version_id Int64,
+                is_applied UInt8,
+                tstamp DateTime DEFAULT now()
+            ) ENGINE = ReplacingMergeTree() ORDER BY version_id;`, tableName, ch.onClusterClause())
+}
+
+func (ch ClickHouseDialect) InsertVersionSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES (?, ?);", tableName)
+}
+
+func (ch ClickHouseDialect) DeleteVersionSQL(tableName string) string {
+	// ClickHouse has no row-level DELETE; mutations are expressed as
+	// ALTER TABLE ... DELETE WHERE and applied asynchronously.
+	return fmt.Sprintf("ALTER TABLE %s DELETE WHERE version_id = ?;", tableName)
+}
+
+func (ch ClickHouseDialect) DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error) {
+	// ReplacingMergeTree only dedupes rows for a given version_id during
+	// background merges, so a plain SELECT right after goose's usual
+	// insert+delete cycle for a version can still see duplicate/stale
+	// rows for it. FINAL forces ClickHouse to apply those merges at
+	// query time instead.
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id, is_applied FROM %s FINAL ORDER BY version_id DESC", tableName))
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, err
+}
+
+func (ch ClickHouseDialect) columnType(c Column) string {
+	switch c.Type {
+	case ColumnInteger:
+		return integerTypeBySize(c.Size, "Int16", "Int32", "Int64")
+	case ColumnText:
+		return "String"
+	case ColumnDatetime:
+		return "DateTime"
+	case ColumnBoolean:
+		return "UInt8"
+	default:
+		return "String"
+	}
+}
+
+// columnDef renders a ClickHouse column definition. Unlike the other
+// dialects, nullability is expressed by wrapping the type in
+// Nullable(...) rather than a NULL/NOT NULL keyword.
+func (ch ClickHouseDialect) columnDef(c Column) (string, error) {
+	if c.Name == "" {
+		return "", fmt.Errorf("goose: column name must not be empty")
+	}
+
+	typ := ch.columnType(c)
+	if c.Nullable {
+		typ = fmt.Sprintf("Nullable(%s)", typ)
+	}
+
+	def := fmt.Sprintf("%s %s", c.Name, typ)
+	switch {
+	case c.DefaultCurrentTimestamp:
+		def += " DEFAULT now()"
+	case c.Default != "":
+		def += " DEFAULT " + c.Default
+	}
+
+	return def, nil
+}
+
+func (ch ClickHouseDialect) CreateTableSQL(table string, cols []Column) (string, error) {
+	defs := make([]string, 0, len(cols))
+	for _, c := range cols {
+		def, err := ch.columnDef(c)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s%s (\n    %s\n) ENGINE = MergeTree() ORDER BY tuple();",
+		table, ch.onClusterClause(), strings.Join(defs, ",\n    ")), nil
+}
+
+func (ch ClickHouseDialect) AlterTableSQL(table string, changes []TableChange) (string, error) {
+	if len(changes) == 0 {
+		return "", fmt.Errorf("goose: no changes given for table %q", table)
+	}
+
+	clauses := make([]string, 0, len(changes))
+	for _, chg := range changes {
+		switch chg.Kind {
+		case AddColumn:
+			def, err := ch.columnDef(chg.Column)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "ADD COLUMN "+def)
+		case ChangeColumn:
+			col := chg.Column
+			col.Name = chg.Name
+			def, err := ch.columnDef(col)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "MODIFY COLUMN "+def)
+		case DropColumn:
+			clauses = append(clauses, "DROP COLUMN "+chg.Name)
+		default:
+			return "", fmt.Errorf("goose: unknown table change kind for table %q", table)
+		}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s%s %s;", table, ch.onClusterClause(), strings.Join(clauses, ", ")), nil
+}
+
+////////////////////////////
+// MSSQL
+////////////////////////////
+
+// MSSQLDialect struct.
+type MSSQLDialect struct{}
+
+func (ms MSSQLDialect) CreateVersionTableSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE TABLE %s (
+                id INT IDENTITY(1,1) NOT NULL,
+                version_id BIGINT NOT NULL,
+                is_applied BIT NOT NULL,
+                tstamp DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME(),
+                PRIMARY KEY(id)
+            );`, tableName)
+}
+
+func (ms MSSQLDialect) InsertVersionSQL(tableName string) string {
+	return fmt.Sprintf("INSERT INTO %s (version_id, is_applied) VALUES (@p1, @p2);", tableName)
+}
+
+func (ms MSSQLDialect) DeleteVersionSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE version_id = @p1;", tableName)
+}
+
+func (ms MSSQLDialect) DbVersionQuery(db *sql.DB, tableName string) (*sql.Rows, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id, is_applied FROM %s ORDER BY id DESC", tableName))
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, err
+}
+
+func (ms MSSQLDialect) columnType(c Column) string {
+	switch c.Type {
+	case ColumnInteger:
+		return integerTypeBySize(c.Size, "SMALLINT", "INT", "BIGINT")
+	case ColumnText:
+		return textTypeBySize(c.Size, "NVARCHAR", "NVARCHAR(MAX)")
+	case ColumnDatetime:
+		return "DATETIME2"
+	case ColumnBoolean:
+		return "BIT"
+	default:
+		return "NVARCHAR(MAX)"
+	}
+}
+
+func (ms MSSQLDialect) CreateTableSQL(table string, cols []Column) (string, error) {
+	defs := make([]string, 0, len(cols)+1)
+	defs = append(defs, "id INT IDENTITY(1,1) NOT NULL")
+	for _, c := range cols {
+		def, err := buildColumnDef("mssql", ms.columnType, c)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+	}
+	defs = append(defs, "PRIMARY KEY(id)")
+
+	return fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", table, strings.Join(defs, ",\n    ")), nil
+}
+
+func (ms MSSQLDialect) AlterTableSQL(table string, changes []TableChange) (string, error) {
+	if len(changes) == 0 {
+		return "", fmt.Errorf("goose: no changes given for table %q", table)
+	}
+
+	clauses := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		switch ch.Kind {
+		case AddColumn:
+			def, err := buildColumnDef("mssql", ms.columnType, ch.Column)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, "ADD "+def)
+		case ChangeColumn:
+			// T-SQL's ALTER COLUMN only accepts a type and nullability;
+			// defaults must be added separately via ADD CONSTRAINT ...
+			// DEFAULT, which AlterTableSQL's single-statement shape
+			// can't express here.
+			if ch.Column.Default != "" || ch.Column.DefaultCurrentTimestamp {
+				return "", fmt.Errorf("goose: mssql cannot set a default via ALTER COLUMN, add it with a separate ADD CONSTRAINT ... DEFAULT statement for %q", ch.Name)
+			}
+			nullability := "NOT NULL"
+			if ch.Column.Nullable {
+				nullability = "NULL"
+			}
+			clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s %s %s", ch.Name, ms.columnType(ch.Column), nullability))
+		case DropColumn:
+			clauses = append(clauses, "DROP COLUMN "+ch.Name)
+		default:
+			return "", fmt.Errorf("goose: unknown table change kind for table %q", table)
+		}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s %s;", table, strings.Join(clauses, ", ")), nil
+}
@@ -0,0 +1,164 @@
+package goose
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildColumnDef(t *testing.T) {
+	tests := []struct {
+		name    string
+		col     Column
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "not null, no default",
+			col:  Column{Name: "age", Type: ColumnInteger},
+			want: "age INTEGER NOT NULL",
+		},
+		{
+			name: "nullable with literal default",
+			col:  Column{Name: "nickname", Type: ColumnText, Nullable: true, Default: "'anon'"},
+			want: "nickname TEXT NULL DEFAULT 'anon'",
+		},
+		{
+			name: "current timestamp default",
+			col:  Column{Name: "created_at", Type: ColumnDatetime, DefaultCurrentTimestamp: true},
+			want: "created_at TIMESTAMP NOT NULL DEFAULT now()",
+		},
+		{
+			name:    "empty name is rejected",
+			col:     Column{Type: ColumnText},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildColumnDef("postgres", PostgresDialect{}.columnType, tt.col)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("buildColumnDef(%+v) = %q, want error", tt.col, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildColumnDef(%+v) returned unexpected error: %v", tt.col, err)
+			}
+			if got != tt.want {
+				t.Errorf("buildColumnDef(%+v) = %q, want %q", tt.col, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegerTypeBySize(t *testing.T) {
+	tests := []struct {
+		size int
+		want string
+	}{
+		{0, "INT"},
+		{1, "TINYINT"},
+		{2, "TINYINT"},
+		{4, "INT"},
+		{8, "BIGINT"},
+	}
+	for _, tt := range tests {
+		if got := integerTypeBySize(tt.size, "TINYINT", "INT", "BIGINT"); got != tt.want {
+			t.Errorf("integerTypeBySize(%d, ...) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestTextTypeBySize(t *testing.T) {
+	if got := textTypeBySize(0, "VARCHAR", "TEXT"); got != "TEXT" {
+		t.Errorf("textTypeBySize(0, ...) = %q, want TEXT", got)
+	}
+	if got := textTypeBySize(64, "VARCHAR", "TEXT"); got != "VARCHAR(64)" {
+		t.Errorf("textTypeBySize(64, ...) = %q, want VARCHAR(64)", got)
+	}
+}
+
+func TestPostgresCreateTableSQL(t *testing.T) {
+	pg := PostgresDialect{}
+	stmt, err := pg.CreateTableSQL("posts", []Column{
+		{Name: "title", Type: ColumnText, Size: 200},
+		{Name: "published", Type: ColumnBoolean, Default: "false"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTableSQL returned unexpected error: %v", err)
+	}
+	for _, want := range []string{"CREATE TABLE posts", "id serial NOT NULL", "title VARCHAR(200) NOT NULL", "published BOOLEAN NOT NULL DEFAULT false", "PRIMARY KEY(id)"} {
+		if !strings.Contains(stmt, want) {
+			t.Errorf("CreateTableSQL() = %q, missing %q", stmt, want)
+		}
+	}
+}
+
+func TestMySQLChangeColumnUsesChangeName(t *testing.T) {
+	// TableChange.Name is the authoritative column name for ChangeColumn;
+	// Column.Name is ignored even if set to something else.
+	m := MySQLDialect{}
+	stmt, err := m.AlterTableSQL("posts", []TableChange{
+		{Kind: ChangeColumn, Name: "body", Column: Column{Name: "ignored", Type: ColumnText}},
+	})
+	if err != nil {
+		t.Fatalf("AlterTableSQL returned unexpected error: %v", err)
+	}
+	want := "ALTER TABLE posts MODIFY COLUMN body TEXT NOT NULL;"
+	if stmt != want {
+		t.Errorf("AlterTableSQL() = %q, want %q", stmt, want)
+	}
+}
+
+func TestSqlite3AlterTableRejectsMultipleChanges(t *testing.T) {
+	m := Sqlite3Dialect{}
+	_, err := m.AlterTableSQL("posts", []TableChange{
+		{Kind: AddColumn, Column: Column{Name: "a", Type: ColumnText}},
+		{Kind: AddColumn, Column: Column{Name: "b", Type: ColumnText}},
+	})
+	if err == nil {
+		t.Fatal("AlterTableSQL() with two changes = nil error, want error")
+	}
+}
+
+func TestSqlite3AlterTableRejectsChangeColumn(t *testing.T) {
+	m := Sqlite3Dialect{}
+	_, err := m.AlterTableSQL("posts", []TableChange{
+		{Kind: ChangeColumn, Name: "body", Column: Column{Name: "body", Type: ColumnText}},
+	})
+	if err == nil {
+		t.Fatal("AlterTableSQL() with ChangeColumn = nil error, want error")
+	}
+}
+
+func TestRedshiftAlterTableRejectsChangeColumn(t *testing.T) {
+	rs := RedshiftDialect{}
+	_, err := rs.AlterTableSQL("posts", []TableChange{
+		{Kind: ChangeColumn, Name: "body", Column: Column{Name: "body", Type: ColumnText}},
+	})
+	if err == nil {
+		t.Fatal("AlterTableSQL() with ChangeColumn = nil error, want error")
+	}
+}
+
+func TestAlterTableSQLRejectsEmptyChanges(t *testing.T) {
+	dialects := map[string]SQLDialect{
+		"postgres":   PostgresDialect{},
+		"mysql":      MySQLDialect{},
+		"tidb":       TiDBDialect{},
+		"clickhouse": ClickHouseDialect{},
+		"mssql":      MSSQLDialect{},
+	}
+	for name, d := range dialects {
+		t.Run(name, func(t *testing.T) {
+			if _, err := d.AlterTableSQL("posts", nil); err == nil {
+				t.Fatalf("%s.AlterTableSQL(table, nil) = nil error, want error", name)
+			}
+			if _, err := d.AlterTableSQL("posts", []TableChange{}); err == nil {
+				t.Fatalf("%s.AlterTableSQL(table, []TableChange{}) = nil error, want error", name)
+			}
+		})
+	}
+}
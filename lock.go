@@ -0,0 +1,256 @@
+package goose
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log"
+)
+
+// lockKey identifies the migration lock goose takes out per database.
+// Override it with SetLockKey if multiple independent goose deployments
+// share one database and must not block each other.
+var lockKey = "goose"
+
+// SetLockKey sets the key used to derive the per-database migration
+// lock taken out by AcquireLock. Deployments that run more than one
+// independent set of migrations against the same database should give
+// each set its own key.
+func SetLockKey(key string) {
+	lockKey = key
+}
+
+// lockKeyHash derives a stable int64 lock id from lockKey, for dialects
+// (Postgres) whose advisory locks are keyed by an integer rather than
+// an arbitrary string. MySQL/TiDB's named locks take lockKey as is.
+func lockKeyHash() int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(lockKey))
+	return int64(h.Sum64())
+}
+
+////////////////////////////
+// Postgres
+////////////////////////////
+
+func (pg PostgresDialect) SupportsTransactionalDDL() bool {
+	return true
+}
+
+func (pg PostgresDialect) AcquireLock(db *sql.DB) (*sql.Conn, func() error, error) {
+	// pg_advisory_lock is session-scoped: the unlock call must run on
+	// the same connection that took the lock out, or pg_advisory_unlock
+	// silently returns false (no Go-level error) while the original
+	// connection keeps holding the lock.
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := lockKeyHash()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, func() error {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+		closeErr := conn.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}, nil
+}
+
+////////////////////////////
+// MySQL
+////////////////////////////
+
+func (m MySQLDialect) SupportsTransactionalDDL() bool {
+	// MySQL DDL implicitly commits the current transaction, so it
+	// cannot participate in a rollback-able migration transaction.
+	return false
+}
+
+func (m MySQLDialect) AcquireLock(db *sql.DB) (*sql.Conn, func() error, error) {
+	// GET_LOCK/RELEASE_LOCK are tied to the connection that acquired the
+	// lock, so both must run on the same pinned connection rather than
+	// whatever the pool hands back for each call.
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var acquired int
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", lockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if acquired != 1 {
+		conn.Close()
+		return nil, nil, fmt.Errorf("goose: failed to acquire MySQL lock %q", lockKey)
+	}
+
+	return conn, func() error {
+		_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockKey)
+		closeErr := conn.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}, nil
+}
+
+////////////////////////////
+// sqlite3
+////////////////////////////
+
+func (m Sqlite3Dialect) SupportsTransactionalDDL() bool {
+	return true
+}
+
+func (m Sqlite3Dialect) AcquireLock(db *sql.DB) (*sql.Conn, func() error, error) {
+	// BEGIN EXCLUSIVE locks the database file for the lifetime of the
+	// transaction, so the lock and its release must stay on the same
+	// connection, and the caller must run its DDL on that same
+	// connection (not the pool) for it to happen inside the lock.
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "BEGIN EXCLUSIVE"); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, func() error {
+		_, err := conn.ExecContext(ctx, "COMMIT")
+		closeErr := conn.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}, nil
+}
+
+////////////////////////////
+// Redshift
+////////////////////////////
+
+func (rs RedshiftDialect) SupportsTransactionalDDL() bool {
+	return true
+}
+
+func (rs RedshiftDialect) AcquireLock(db *sql.DB) (*sql.Conn, func() error, error) {
+	// Redshift has no advisory locking primitive. Warn loudly and fall
+	// back to a no-op so concurrent migrators are at least visible in
+	// the logs instead of silently racing.
+	log.Printf("goose: redshift has no advisory locks, skipping migration lock %q", lockKey)
+	return nil, func() error { return nil }, nil
+}
+
+////////////////////////////
+// TiDB
+////////////////////////////
+
+func (m TiDBDialect) SupportsTransactionalDDL() bool {
+	// TiDB schema changes run asynchronously outside the issuing
+	// transaction and cannot be rolled back with it.
+	return false
+}
+
+func (m TiDBDialect) AcquireLock(db *sql.DB) (*sql.Conn, func() error, error) {
+	// Like MySQL, GET_LOCK/RELEASE_LOCK are tied to the connection that
+	// acquired the lock.
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var acquired int
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", lockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if acquired != 1 {
+		conn.Close()
+		return nil, nil, fmt.Errorf("goose: failed to acquire TiDB lock %q", lockKey)
+	}
+
+	return conn, func() error {
+		_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockKey)
+		closeErr := conn.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}, nil
+}
+
+////////////////////////////
+// ClickHouse
+////////////////////////////
+
+func (ch ClickHouseDialect) SupportsTransactionalDDL() bool {
+	// Most ClickHouse DDL is non-transactional and, with OnCluster
+	// set, applied asynchronously per-node.
+	return false
+}
+
+func (ch ClickHouseDialect) AcquireLock(db *sql.DB) (*sql.Conn, func() error, error) {
+	// ClickHouse has no advisory locking primitive. Warn loudly and
+	// fall back to a no-op so concurrent migrators are at least
+	// visible in the logs instead of silently racing.
+	log.Printf("goose: clickhouse has no advisory locks, skipping migration lock %q", lockKey)
+	return nil, func() error { return nil }, nil
+}
+
+////////////////////////////
+// MSSQL
+////////////////////////////
+
+func (ms MSSQLDialect) SupportsTransactionalDDL() bool {
+	return true
+}
+
+func (ms MSSQLDialect) AcquireLock(db *sql.DB) (*sql.Conn, func() error, error) {
+	// @LockOwner = 'Session' ties the lock to the connection that took
+	// it out, so acquire and release must run on the same connection
+	// rather than whatever the pool hands back for each call.
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// sp_getapplock reports its status via a procedure return code, not
+	// a result set, so capture it into a variable and SELECT it back.
+	var result int
+	query := `DECLARE @result int;
+		EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session';
+		SELECT @result;`
+	if err := conn.QueryRowContext(ctx, query, lockKey).Scan(&result); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if result < 0 {
+		conn.Close()
+		return nil, nil, fmt.Errorf("goose: failed to acquire MSSQL app lock %q (sp_getapplock returned %d)", lockKey, result)
+	}
+
+	return conn, func() error {
+		_, err := conn.ExecContext(ctx, "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", lockKey)
+		closeErr := conn.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}, nil
+}
@@ -0,0 +1,179 @@
+package goose
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeLockDriver is a minimal database/sql/driver whose only job is to
+// record which physical connection each statement ran on, so tests can
+// assert that a dialect's AcquireLock pins a single connection for its
+// acquire and release calls rather than letting the pool hand back a
+// different one for each.
+type fakeLockDriver struct {
+	mu    sync.Mutex
+	calls []lockCall
+	next  int32
+}
+
+type lockCall struct {
+	connID int32
+	query  string
+}
+
+var fakeLockDriverSeq int32
+
+func registerFakeLockDriver() (string, *fakeLockDriver) {
+	d := &fakeLockDriver{}
+	name := fmt.Sprintf("fakelock%d", atomic.AddInt32(&fakeLockDriverSeq, 1))
+	sql.Register(name, d)
+	return name, d
+}
+
+func (d *fakeLockDriver) Open(name string) (driver.Conn, error) {
+	id := atomic.AddInt32(&d.next, 1)
+	return &fakeLockConn{driver: d, id: id}, nil
+}
+
+// connIDsFor returns the connection id used by each call whose query
+// contains substr, in call order.
+func (d *fakeLockDriver) connIDsFor(substr string) []int32 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var ids []int32
+	for _, c := range d.calls {
+		if strings.Contains(c.query, substr) {
+			ids = append(ids, c.connID)
+		}
+	}
+	return ids
+}
+
+type fakeLockConn struct {
+	driver *fakeLockDriver
+	id     int32
+}
+
+func (c *fakeLockConn) record(query string) {
+	c.driver.mu.Lock()
+	c.driver.calls = append(c.driver.calls, lockCall{connID: c.id, query: query})
+	c.driver.mu.Unlock()
+}
+
+func (c *fakeLockConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakeLockConn: Prepare not supported")
+}
+
+func (c *fakeLockConn) Close() error { return nil }
+
+func (c *fakeLockConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakeLockConn: Begin not supported")
+}
+
+func (c *fakeLockConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.record(query)
+	return driver.ResultNoRows, nil
+}
+
+func (c *fakeLockConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.record(query)
+	return &fakeLockRows{val: 1}, nil
+}
+
+// fakeLockRows yields a single row with a single int64 column, enough
+// for AcquireLock implementations that Scan a lock-acquired flag.
+type fakeLockRows struct {
+	val  int64
+	done bool
+}
+
+func (r *fakeLockRows) Columns() []string { return []string{"result"} }
+func (r *fakeLockRows) Close() error      { return nil }
+func (r *fakeLockRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.val
+	return nil
+}
+
+func testAcquireLockPinsConnection(t *testing.T, dialectName string, d SQLDialect, acquireSubstr, releaseSubstr string) {
+	t.Helper()
+
+	name, fd := registerFakeLockDriver()
+	db, err := sql.Open(name, "fake")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(5)
+
+	conn, unlock, err := d.AcquireLock(db)
+	if err != nil {
+		t.Fatalf("%s.AcquireLock: %v", dialectName, err)
+	}
+	if conn == nil {
+		t.Fatalf("%s.AcquireLock returned a nil *sql.Conn", dialectName)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("%s unlock(): %v", dialectName, err)
+	}
+
+	acquireIDs := fd.connIDsFor(acquireSubstr)
+	releaseIDs := fd.connIDsFor(releaseSubstr)
+	if len(acquireIDs) != 1 || len(releaseIDs) != 1 {
+		t.Fatalf("%s: got acquire calls %v, release calls %v, want exactly one of each", dialectName, acquireIDs, releaseIDs)
+	}
+	if acquireIDs[0] != releaseIDs[0] {
+		t.Errorf("%s: acquire ran on connection %d but release ran on connection %d, want the same connection", dialectName, acquireIDs[0], releaseIDs[0])
+	}
+}
+
+func TestPostgresAcquireLockPinsConnection(t *testing.T) {
+	testAcquireLockPinsConnection(t, "postgres", PostgresDialect{}, "pg_advisory_lock", "pg_advisory_unlock")
+}
+
+func TestMySQLAcquireLockPinsConnection(t *testing.T) {
+	testAcquireLockPinsConnection(t, "mysql", MySQLDialect{}, "GET_LOCK", "RELEASE_LOCK")
+}
+
+func TestTiDBAcquireLockPinsConnection(t *testing.T) {
+	testAcquireLockPinsConnection(t, "tidb", TiDBDialect{}, "GET_LOCK", "RELEASE_LOCK")
+}
+
+func TestSqlite3AcquireLockPinsConnection(t *testing.T) {
+	testAcquireLockPinsConnection(t, "sqlite3", Sqlite3Dialect{}, "BEGIN EXCLUSIVE", "COMMIT")
+}
+
+func TestMSSQLAcquireLockPinsConnection(t *testing.T) {
+	testAcquireLockPinsConnection(t, "mssql", MSSQLDialect{}, "sp_getapplock", "sp_releaseapplock")
+}
+
+func TestRedshiftAcquireLockIsNoopWithoutConnection(t *testing.T) {
+	name, _ := registerFakeLockDriver()
+	db, err := sql.Open(name, "fake")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	conn, unlock, err := RedshiftDialect{}.AcquireLock(db)
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	if conn != nil {
+		t.Errorf("AcquireLock returned a non-nil *sql.Conn for a no-op lock")
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("unlock(): %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package goose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// columnTypeMapper maps a portable Column to a dialect's native column
+// type, e.g. "BIGINT" or "VARCHAR(255)".
+type columnTypeMapper func(c Column) string
+
+// currentTimestampExprs holds each dialect's "now" default expression,
+// used when Column.DefaultCurrentTimestamp is set.
+var currentTimestampExprs = map[string]string{
+	"postgres": "now()",
+	"mysql":    "CURRENT_TIMESTAMP",
+	"sqlite3":  "(datetime('now'))",
+	"redshift": "sysdate",
+	"tidb":     "now()",
+	"mssql":    "SYSUTCDATETIME()",
+}
+
+// buildColumnDef renders "<name> <type> [NULL|NOT NULL] [DEFAULT ...]"
+// for a single column, using the given dialect's type mapper and
+// current-timestamp expression.
+func buildColumnDef(dialectName string, mapColumnType columnTypeMapper, c Column) (string, error) {
+	if c.Name == "" {
+		return "", fmt.Errorf("goose: column name must not be empty")
+	}
+
+	parts := []string{c.Name, mapColumnType(c)}
+
+	if c.Nullable {
+		parts = append(parts, "NULL")
+	} else {
+		parts = append(parts, "NOT NULL")
+	}
+
+	switch {
+	case c.DefaultCurrentTimestamp:
+		expr, ok := currentTimestampExprs[dialectName]
+		if !ok {
+			return "", fmt.Errorf("goose: %q dialect has no current timestamp default", dialectName)
+		}
+		parts = append(parts, "DEFAULT "+expr)
+	case c.Default != "":
+		parts = append(parts, "DEFAULT "+c.Default)
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// integerTypeBySize picks among three integer type names based on
+// Column.Size (in bytes): small, normal, big. A Size of 0 selects normal.
+func integerTypeBySize(size int, small, normal, big string) string {
+	switch {
+	case size > 0 && size <= 2:
+		return small
+	case size >= 8:
+		return big
+	default:
+		return normal
+	}
+}
+
+// textTypeBySize returns a sized varchar type when Size is set, and
+// textType otherwise.
+func textTypeBySize(size int, varchar, textType string) string {
+	if size > 0 {
+		return fmt.Sprintf("%s(%d)", varchar, size)
+	}
+	return textType
+}
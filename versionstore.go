@@ -0,0 +1,135 @@
+package goose
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// VersionRecord is a single row tracked by a VersionStore: a migration
+// version that has been applied (or rolled back).
+type VersionRecord struct {
+	VersionID int64
+	IsApplied bool
+	Tstamp    time.Time
+}
+
+// VersionStore abstracts how goose tracks which migrations have been
+// applied. The default store, SQLVersionStore, keeps a version table in
+// the target database. Alternatives can be swapped in with
+// SetVersionStore for cases the default doesn't fit: a file-based store
+// for databases the migrator can't create tables in, a schema-qualified
+// table, or a namespaced table so multiple independent migration sets
+// can share one database.
+type VersionStore interface {
+	EnsureTable(db *sql.DB) error
+	Insert(db *sql.DB, version int64, applied bool) error
+	Delete(db *sql.DB, version int64) error
+	List(db *sql.DB) ([]VersionRecord, error)
+}
+
+const defaultVersionTableName = "goose_db_version"
+
+// tableNamePattern restricts version table names to plain identifiers
+// (optionally schema-qualified with a single dot), since TableName is
+// interpolated directly into SQL by every SQLDialect method and must
+// never carry caller-controlled SQL syntax.
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var store VersionStore = NewSQLVersionStore("")
+
+// GetVersionStore gets the VersionStore.
+func GetVersionStore() VersionStore {
+	return store
+}
+
+// SetVersionStore allows you to pass in a non-standard VersionStore.
+func SetVersionStore(s VersionStore) {
+	store = s
+}
+
+// SQLVersionStore is the default VersionStore. It renders its SQL
+// through the current SQLDialect (see GetDialect), so it automatically
+// tracks dialect switches made via SetDialect/SetCustomDialect.
+// TableName defaults to "goose_db_version" but may be overridden, e.g.
+// to schema-qualify it ("myschema.goose_db_version") or to namespace
+// multiple independent migration sets within one database.
+type SQLVersionStore struct {
+	TableName string
+}
+
+// NewSQLVersionStore creates a SQLVersionStore for tableName. An empty
+// tableName falls back to "goose_db_version".
+func NewSQLVersionStore(tableName string) *SQLVersionStore {
+	if tableName == "" {
+		tableName = defaultVersionTableName
+	}
+	return &SQLVersionStore{TableName: tableName}
+}
+
+// tableName returns the table name to use, validating it as a plain
+// (optionally schema-qualified) SQL identifier. This guards against the
+// name reaching a dialect method's fmt.Sprintf-built SQL unescaped,
+// whether it came through NewSQLVersionStore or a direct TableName
+// assignment.
+func (s *SQLVersionStore) tableName() (string, error) {
+	name := s.TableName
+	if name == "" {
+		name = defaultVersionTableName
+	}
+	if !tableNamePattern.MatchString(name) {
+		return "", fmt.Errorf("goose: invalid version table name %q", name)
+	}
+	return name, nil
+}
+
+func (s *SQLVersionStore) EnsureTable(db *sql.DB) error {
+	tableName, err := s.tableName()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(GetDialect().CreateVersionTableSQL(tableName))
+	return err
+}
+
+func (s *SQLVersionStore) Insert(db *sql.DB, version int64, applied bool) error {
+	tableName, err := s.tableName()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(GetDialect().InsertVersionSQL(tableName), version, applied)
+	return err
+}
+
+func (s *SQLVersionStore) Delete(db *sql.DB, version int64) error {
+	tableName, err := s.tableName()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(GetDialect().DeleteVersionSQL(tableName), version)
+	return err
+}
+
+func (s *SQLVersionStore) List(db *sql.DB) ([]VersionRecord, error) {
+	tableName, err := s.tableName()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := GetDialect().DbVersionQuery(db, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []VersionRecord
+	for rows.Next() {
+		var r VersionRecord
+		if err := rows.Scan(&r.VersionID, &r.IsApplied); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
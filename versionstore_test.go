@@ -0,0 +1,70 @@
+package goose
+
+import "testing"
+
+func TestSQLVersionStoreDefaultsTableName(t *testing.T) {
+	s := NewSQLVersionStore("")
+	name, err := s.tableName()
+	if err != nil {
+		t.Fatalf("tableName() returned unexpected error: %v", err)
+	}
+	if name != defaultVersionTableName {
+		t.Errorf("tableName() = %q, want %q", name, defaultVersionTableName)
+	}
+}
+
+func TestSQLVersionStoreAcceptsValidTableNames(t *testing.T) {
+	for _, name := range []string{"goose_db_version", "_migrations", "myschema.goose_db_version", "tenant_a_migrations"} {
+		s := NewSQLVersionStore(name)
+		got, err := s.tableName()
+		if err != nil {
+			t.Errorf("tableName() for %q returned unexpected error: %v", name, err)
+		}
+		if got != name {
+			t.Errorf("tableName() for %q = %q", name, got)
+		}
+	}
+}
+
+func TestSQLVersionStoreRejectsInvalidTableNames(t *testing.T) {
+	// These would otherwise be spliced unescaped into CREATE TABLE/DELETE
+	// FROM/etc via fmt.Sprintf.
+	for _, name := range []string{
+		"goose_db_version; DROP TABLE users",
+		"goose db version",
+		"goose'; --",
+		"a.b.c",
+		"1migrations",
+	} {
+		s := NewSQLVersionStore(name)
+		if _, err := s.tableName(); err == nil {
+			t.Errorf("tableName() for %q = nil error, want error", name)
+		}
+	}
+}
+
+func TestSQLVersionStoreRejectsTableNameSetDirectly(t *testing.T) {
+	// Validation must also catch a TableName set directly on the struct,
+	// not just names passed through NewSQLVersionStore.
+	s := &SQLVersionStore{TableName: "bad; name"}
+	if _, err := s.tableName(); err == nil {
+		t.Fatal("tableName() = nil error, want error")
+	}
+}
+
+func TestSQLVersionStoreMethodsRejectInvalidTableName(t *testing.T) {
+	s := &SQLVersionStore{TableName: "bad; name"}
+
+	if err := s.EnsureTable(nil); err == nil {
+		t.Error("EnsureTable() = nil error, want error")
+	}
+	if err := s.Insert(nil, 1, true); err == nil {
+		t.Error("Insert() = nil error, want error")
+	}
+	if err := s.Delete(nil, 1); err == nil {
+		t.Error("Delete() = nil error, want error")
+	}
+	if _, err := s.List(nil); err == nil {
+		t.Error("List() = nil error, want error")
+	}
+}